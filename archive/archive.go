@@ -0,0 +1,66 @@
+// Package archive uploads finished job logs to long-term object storage
+// once the processor pool is done streaming them to the build log API.
+package archive
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ArchiveUploader pushes a single gzip-compressed log file, identified by
+// jobID, to an archive backend and returns the URL it was stored at.
+type ArchiveUploader interface {
+	Upload(jobID uint64, gzippedLog []byte) (url string, err error)
+}
+
+// Spooler accepts a gzip-compressed job log for archival without uploading
+// it synchronously. DirectoryArchiveManager implements this by writing the
+// log to its spool directory, so a crash between Spool and a successful
+// upload just leaves the file for the next sweep to pick back up.
+type Spooler interface {
+	Spool(jobID uint64, gzippedLog []byte) error
+}
+
+// Config holds the settings needed to construct an ArchiveUploader. It is
+// parsed from TRAVIS_WORKER_ARCHIVE_* environment variables the same way
+// provider configuration is parsed from TRAVIS_WORKER_<PROVIDER>_*.
+type Config struct {
+	Bucket string
+	Region string
+	Prefix string
+	SSEKey string
+}
+
+// ConfigFromEnviron builds a Config from TRAVIS_WORKER_ARCHIVE_* environment
+// variables, mirroring main.ProviderConfigFromEnviron.
+func ConfigFromEnviron(environ []string) Config {
+	const prefix = "TRAVIS_WORKER_ARCHIVE_"
+
+	values := make(map[string]string)
+	for _, e := range environ {
+		if !strings.HasPrefix(e, prefix) {
+			continue
+		}
+
+		pair := strings.SplitN(e, "=", 2)
+		key := strings.ToLower(strings.TrimPrefix(pair[0], prefix))
+		values[key] = pair[1]
+	}
+
+	return Config{
+		Bucket: values["bucket"],
+		Region: values["region"],
+		Prefix: values["prefix"],
+		SSEKey: values["sse_key"],
+	}
+}
+
+// Key returns the object storage key a job's archived log should be stored
+// under.
+func (c Config) Key(jobID uint64) string {
+	if c.Prefix == "" {
+		return fmt.Sprintf("%d.log.gz", jobID)
+	}
+
+	return fmt.Sprintf("%s/%d.log.gz", strings.TrimSuffix(c.Prefix, "/"), jobID)
+}
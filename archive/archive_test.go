@@ -0,0 +1,38 @@
+package archive
+
+import "testing"
+
+func TestConfigKey(t *testing.T) {
+	cases := []struct {
+		config Config
+		jobID  uint64
+		want   string
+	}{
+		{Config{}, 123, "123.log.gz"},
+		{Config{Prefix: "logs"}, 123, "logs/123.log.gz"},
+		{Config{Prefix: "logs/"}, 123, "logs/123.log.gz"},
+	}
+
+	for _, c := range cases {
+		if got := c.config.Key(c.jobID); got != c.want {
+			t.Errorf("Config{Prefix: %q}.Key(%d) = %q, want %q", c.config.Prefix, c.jobID, got, c.want)
+		}
+	}
+}
+
+func TestConfigFromEnviron(t *testing.T) {
+	environ := []string{
+		"TRAVIS_WORKER_ARCHIVE_BUCKET=travis-logs",
+		"TRAVIS_WORKER_ARCHIVE_REGION=us-east-1",
+		"TRAVIS_WORKER_ARCHIVE_PREFIX=prod",
+		"TRAVIS_WORKER_ARCHIVE_SSE_KEY=secret",
+		"TRAVIS_WORKER_OTHER_THING=ignored",
+	}
+
+	got := ConfigFromEnviron(environ)
+	want := Config{Bucket: "travis-logs", Region: "us-east-1", Prefix: "prod", SSEKey: "secret"}
+
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
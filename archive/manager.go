@@ -0,0 +1,125 @@
+package archive
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	uploadsCounter  = metrics.GetOrRegisterCounter("travis.worker.archive.uploads", metrics.DefaultRegistry)
+	failuresCounter = metrics.GetOrRegisterCounter("travis.worker.archive.failures", metrics.DefaultRegistry)
+	spoolDepthGauge = metrics.GetOrRegisterGauge("travis.worker.archive.spool-depth", metrics.DefaultRegistry)
+)
+
+// DirectoryArchiveManager spools gzipped job logs to a directory and
+// periodically sweeps it, uploading each file with ArchiveUploader and
+// retrying failures with exponential backoff. A worker crash between
+// spooling and a successful upload simply leaves the file for the next
+// sweep to pick back up.
+type DirectoryArchiveManager struct {
+	SpoolDir      string
+	SweepInterval time.Duration
+	MaxBackoff    time.Duration
+
+	uploader ArchiveUploader
+	retryAt  map[string]time.Time
+	backoff  map[string]time.Duration
+}
+
+// NewDirectoryArchiveManager builds a manager that spools to spoolDir and
+// sweeps it every sweepInterval, uploading via uploader.
+func NewDirectoryArchiveManager(spoolDir string, sweepInterval time.Duration, uploader ArchiveUploader) *DirectoryArchiveManager {
+	return &DirectoryArchiveManager{
+		SpoolDir:      spoolDir,
+		SweepInterval: sweepInterval,
+		MaxBackoff:    15 * time.Minute,
+		uploader:      uploader,
+		retryAt:       make(map[string]time.Time),
+		backoff:       make(map[string]time.Duration),
+	}
+}
+
+// Spool writes a finished job's gzipped log to the spool directory so it
+// survives a crash until the next sweep uploads it.
+func (m *DirectoryArchiveManager) Spool(jobID uint64, gzippedLog []byte) error {
+	path := filepath.Join(m.SpoolDir, strconv.FormatUint(jobID, 10)+".log.gz")
+	return ioutil.WriteFile(path, gzippedLog, 0644)
+}
+
+// Run sweeps the spool directory every SweepInterval until stop is closed.
+func (m *DirectoryArchiveManager) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(m.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.sweep()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (m *DirectoryArchiveManager) sweep() {
+	entries, err := ioutil.ReadDir(m.SpoolDir)
+	if err != nil {
+		return
+	}
+
+	spoolDepthGauge.Update(int64(len(entries)))
+
+	now := time.Now()
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".log.gz") {
+			continue
+		}
+
+		if retryAt, ok := m.retryAt[entry.Name()]; ok && now.Before(retryAt) {
+			continue
+		}
+
+		m.uploadOne(entry.Name())
+	}
+}
+
+func (m *DirectoryArchiveManager) uploadOne(name string) {
+	path := filepath.Join(m.SpoolDir, name)
+
+	jobID, err := strconv.ParseUint(strings.TrimSuffix(name, ".log.gz"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	gzippedLog, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	if _, err := m.uploader.Upload(jobID, gzippedLog); err != nil {
+		failuresCounter.Inc(1)
+		m.scheduleRetry(name)
+		return
+	}
+
+	uploadsCounter.Inc(1)
+	delete(m.retryAt, name)
+	delete(m.backoff, name)
+	os.Remove(path)
+}
+
+func (m *DirectoryArchiveManager) scheduleRetry(name string) {
+	next := m.backoff[name]*2 + time.Second
+	if next > m.MaxBackoff {
+		next = m.MaxBackoff
+	}
+
+	m.backoff[name] = next
+	m.retryAt[name] = time.Now().Add(next)
+}
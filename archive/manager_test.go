@@ -0,0 +1,116 @@
+package archive
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fakeUploader struct {
+	failNext bool
+	uploaded map[uint64][]byte
+}
+
+func (u *fakeUploader) Upload(jobID uint64, gzippedLog []byte) (string, error) {
+	if u.failNext {
+		return "", errors.New("fake upload failure")
+	}
+
+	if u.uploaded == nil {
+		u.uploaded = make(map[uint64][]byte)
+	}
+	u.uploaded[jobID] = gzippedLog
+
+	return "fake://" + filepath.Join("bucket", "123"), nil
+}
+
+func TestDirectoryArchiveManagerSpoolThenSweepUploadsAndRemoves(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	uploader := &fakeUploader{}
+	m := NewDirectoryArchiveManager(dir, time.Minute, uploader)
+
+	if err := m.Spool(42, []byte("log bytes")); err != nil {
+		t.Fatalf("Spool: %v", err)
+	}
+
+	spooledPath := filepath.Join(dir, "42.log.gz")
+	if _, err := os.Stat(spooledPath); err != nil {
+		t.Fatalf("spooled file missing: %v", err)
+	}
+
+	m.sweep()
+
+	if got := uploader.uploaded[42]; string(got) != "log bytes" {
+		t.Errorf("got uploaded payload %q, want %q", got, "log bytes")
+	}
+
+	if _, err := os.Stat(spooledPath); !os.IsNotExist(err) {
+		t.Errorf("got spooled file still present after a successful upload, want it removed")
+	}
+}
+
+func TestDirectoryArchiveManagerRetriesFailuresWithBackoff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	uploader := &fakeUploader{failNext: true}
+	m := NewDirectoryArchiveManager(dir, time.Minute, uploader)
+
+	if err := m.Spool(7, []byte("will fail first")); err != nil {
+		t.Fatalf("Spool: %v", err)
+	}
+
+	m.sweep()
+
+	spooledPath := filepath.Join(dir, "7.log.gz")
+	if _, err := os.Stat(spooledPath); err != nil {
+		t.Fatalf("spooled file should survive a failed upload: %v", err)
+	}
+
+	if m.backoff["7.log.gz"] != time.Second {
+		t.Errorf("got first backoff %v, want %v", m.backoff["7.log.gz"], time.Second)
+	}
+
+	if !m.retryAt["7.log.gz"].After(time.Now()) {
+		t.Error("retryAt should be scheduled in the future after a failure")
+	}
+
+	// A sweep before the retry is due should not attempt another upload.
+	uploader.failNext = false
+	m.sweep()
+
+	if _, ok := uploader.uploaded[7]; ok {
+		t.Error("sweep uploaded a file before its backoff expired")
+	}
+}
+
+func TestDirectoryArchiveManagerScheduleRetryDoublesUpToMax(t *testing.T) {
+	m := NewDirectoryArchiveManager("", time.Minute, &fakeUploader{})
+	m.MaxBackoff = 4 * time.Second
+
+	m.scheduleRetry("x")
+	if m.backoff["x"] != time.Second {
+		t.Fatalf("got first backoff %v, want %v", m.backoff["x"], time.Second)
+	}
+
+	m.scheduleRetry("x")
+	if m.backoff["x"] != 3*time.Second {
+		t.Fatalf("got second backoff %v, want %v", m.backoff["x"], 3*time.Second)
+	}
+
+	m.scheduleRetry("x")
+	if m.backoff["x"] != m.MaxBackoff {
+		t.Fatalf("got third backoff %v, want capped at %v", m.backoff["x"], m.MaxBackoff)
+	}
+}
@@ -0,0 +1,52 @@
+package archive
+
+import (
+	"bytes"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// S3Uploader is an ArchiveUploader backed by an S3-compatible object store.
+type S3Uploader struct {
+	config Config
+	client *s3.S3
+}
+
+// NewS3Uploader builds an S3Uploader from config, dialing the region given
+// in config.Region.
+func NewS3Uploader(config Config) *S3Uploader {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region: aws.String(config.Region),
+	}))
+
+	return &S3Uploader{
+		config: config,
+		client: s3.New(sess),
+	}
+}
+
+// Upload implements ArchiveUploader.
+func (u *S3Uploader) Upload(jobID uint64, gzippedLog []byte) (string, error) {
+	key := u.config.Key(jobID)
+
+	input := &s3.PutObjectInput{
+		Bucket:          aws.String(u.config.Bucket),
+		Key:             aws.String(key),
+		Body:            bytes.NewReader(gzippedLog),
+		ContentEncoding: aws.String("gzip"),
+		ContentType:     aws.String("text/plain"),
+	}
+
+	if u.config.SSEKey != "" {
+		input.SSECustomerAlgorithm = aws.String("AES256")
+		input.SSECustomerKey = aws.String(u.config.SSEKey)
+	}
+
+	if _, err := u.client.PutObject(input); err != nil {
+		return "", err
+	}
+
+	return "s3://" + u.config.Bucket + "/" + key, nil
+}
@@ -0,0 +1,81 @@
+package worker
+
+import "sync"
+
+// BrokerHealth tracks the state of the worker's connections to external
+// services that the processor pool depends on in order to dispatch jobs.
+// It is safe for concurrent use.
+type BrokerHealth struct {
+	mu sync.RWMutex
+
+	RabbitConnected    bool
+	RabbitBackPressure bool
+	DbConnected        bool
+	ShuttingDown       bool
+}
+
+// NewBrokerHealth returns a BrokerHealth with all fields zeroed, representing
+// a worker that has not yet connected to anything.
+func NewBrokerHealth() *BrokerHealth {
+	return &BrokerHealth{}
+}
+
+// SetRabbitConnected updates whether the AMQP connection is currently up.
+func (h *BrokerHealth) SetRabbitConnected(connected bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.RabbitConnected = connected
+}
+
+// SetRabbitBackPressure updates whether RabbitMQ has asked the worker to
+// stop publishing via a connection.blocked frame.
+func (h *BrokerHealth) SetRabbitBackPressure(blocked bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.RabbitBackPressure = blocked
+}
+
+// SetDbConnected updates whether the backend database is reachable.
+func (h *BrokerHealth) SetDbConnected(connected bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.DbConnected = connected
+}
+
+// SetShuttingDown marks whether the worker has begun tearing down, so the
+// diagnostics server can report not-ready ahead of the shutdown actually
+// completing.
+func (h *BrokerHealth) SetShuttingDown(shuttingDown bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ShuttingDown = shuttingDown
+}
+
+// Snapshot returns a copy of the current health fields, safe to read without
+// holding any lock.
+func (h *BrokerHealth) Snapshot() BrokerHealth {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return BrokerHealth{
+		RabbitConnected:    h.RabbitConnected,
+		RabbitBackPressure: h.RabbitBackPressure,
+		DbConnected:        h.DbConnected,
+		ShuttingDown:       h.ShuttingDown,
+	}
+}
+
+// IsHealthy reports whether the worker can currently dispatch jobs and
+// publish log chunks.
+func (h *BrokerHealth) IsHealthy() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.RabbitConnected && !h.RabbitBackPressure
+}
+
+// Ready implements diagnostic.ReadinessChecker: the worker is ready to
+// accept jobs when it's healthy and hasn't started shutting down.
+func (h *BrokerHealth) Ready() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.RabbitConnected && !h.RabbitBackPressure && !h.ShuttingDown
+}
@@ -0,0 +1,52 @@
+package worker
+
+import "testing"
+
+func TestBrokerHealthIsHealthy(t *testing.T) {
+	h := NewBrokerHealth()
+
+	if h.IsHealthy() {
+		t.Error("got healthy with nothing connected, want unhealthy")
+	}
+
+	h.SetRabbitConnected(true)
+	if !h.IsHealthy() {
+		t.Error("got unhealthy once connected, want healthy")
+	}
+
+	h.SetRabbitBackPressure(true)
+	if h.IsHealthy() {
+		t.Error("got healthy while backpressured, want unhealthy")
+	}
+
+	h.SetRabbitBackPressure(false)
+	if !h.IsHealthy() {
+		t.Error("got unhealthy once unblocked, want healthy")
+	}
+}
+
+func TestBrokerHealthReady(t *testing.T) {
+	h := NewBrokerHealth()
+	h.SetRabbitConnected(true)
+
+	if !h.Ready() {
+		t.Error("got not-ready while healthy and not shutting down, want ready")
+	}
+
+	h.SetShuttingDown(true)
+	if h.Ready() {
+		t.Error("got ready while shutting down, want not-ready")
+	}
+}
+
+func TestBrokerHealthSnapshotIsACopy(t *testing.T) {
+	h := NewBrokerHealth()
+	h.SetRabbitConnected(true)
+
+	snap := h.Snapshot()
+	h.SetRabbitConnected(false)
+
+	if !snap.RabbitConnected {
+		t.Error("mutating the source after Snapshot mutated the snapshot too")
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/travis-ci/worker"
+)
+
+var checkConfigCmd = &cobra.Command{
+	Use:   "check-config",
+	Short: "Parse and print the resolved configuration",
+	RunE:  checkConfig,
+}
+
+func checkConfig(cmd *cobra.Command, args []string) error {
+	config, err := worker.LoadConfig(v)
+	if err != nil {
+		return fmt.Errorf("couldn't load config: %v", err)
+	}
+
+	fmt.Printf("%+v\n", config)
+
+	return nil
+}
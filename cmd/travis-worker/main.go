@@ -1,155 +1,60 @@
+// Command travis-worker runs the Travis CI worker daemon.
 package main
 
 import (
 	"fmt"
-	"log"
-	"net/http"
-	_ "net/http/pprof"
 	"os"
-	"os/signal"
 	"strings"
-	"syscall"
-	"time"
 
-	"github.com/Sirupsen/logrus"
-	"github.com/Sirupsen/logrus/hooks/sentry"
-	"github.com/codegangsta/cli"
-	"github.com/rcrowley/go-metrics"
-	"github.com/rcrowley/go-metrics/librato"
-	"github.com/streadway/amqp"
-	"github.com/travis-ci/worker"
-	"github.com/travis-ci/worker/backend"
-	"github.com/travis-ci/worker/context"
-	gocontext "golang.org/x/net/context"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
-func main() {
-	app := cli.NewApp()
-	app.Usage = "Travis Worker daemon"
-	app.Version = worker.VersionString
-	app.Author = "Travis CI GmbH"
-	app.Email = "contact+travis-worker@travis-ci.org"
-
-	app.Flags = worker.Flags
-	app.Action = runWorker
+var (
+	cfgFile string
+	v       = viper.New()
+)
 
-	app.Run(os.Args)
+var rootCmd = &cobra.Command{
+	Use:   "travis-worker",
+	Short: "Travis Worker daemon",
 }
 
-func runWorker(c *cli.Context) {
-	ctx, cancel := gocontext.WithCancel(gocontext.Background())
-	logger := context.LoggerFromContext(ctx)
-
-	logrus.SetFormatter(&logrus.TextFormatter{DisableColors: true})
-
-	if c.String("pprof-port") != "" {
-		// Start net/http/pprof server
-		go func() {
-			http.ListenAndServe(fmt.Sprintf("localhost:%s", c.String("pprof-port")), nil)
-		}()
-	}
-
-	if c.Bool("debug") {
-		logrus.SetLevel(logrus.DebugLevel)
-	}
-
-	logger.Info("worker started")
-	defer logger.Info("worker finished")
-
-	config := worker.ConfigFromCLIContext(c)
+func main() {
+	cobra.OnInitialize(initConfig)
 
-	logger.WithField("config", fmt.Sprintf("%+v", config)).Debug("read config")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default /etc/travis-worker.yaml)")
 
-	if config.SentryDSN != "" {
-		sentryHook, err := logrus_sentry.NewSentryHook(config.SentryDSN, []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel})
-		if err != nil {
-			context.LoggerFromContext(ctx).WithField("err", err).Error("couldn't create sentry hook")
-		}
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(checkConfigCmd)
+	rootCmd.AddCommand(versionCmd)
 
-		logrus.AddHook(sentryHook)
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
+}
 
-	if config.LibratoEmail != "" && config.LibratoToken != "" && config.LibratoSource != "" {
-		context.LoggerFromContext(ctx).Info("starting librato metrics reporter")
-		go librato.Librato(metrics.DefaultRegistry, time.Minute, config.LibratoEmail, config.LibratoToken, config.LibratoSource, []float64{0.95}, time.Millisecond)
+// initConfig wires up viper's layered configuration: built-in defaults are
+// set by the flag definitions in run.go's init, a config file is loaded if
+// present, environment variables prefixed TRAVIS_WORKER_ override the file,
+// and CLI flags (already bound in that init) override everything.
+func initConfig() {
+	if cfgFile != "" {
+		v.SetConfigFile(cfgFile)
 	} else {
-		context.LoggerFromContext(ctx).Info("starting logger metrics reporter")
-		go metrics.Log(metrics.DefaultRegistry, time.Minute, log.New(os.Stderr, "metrics: ", log.Lmicroseconds))
-	}
-
-	amqpConn, err := amqp.Dial(config.AmqpURI)
-	if err != nil {
-		context.LoggerFromContext(ctx).WithField("err", err).Error("couldn't connect to AMQP")
-		return
-	}
-
-	go func() {
-		errChan := make(chan *amqp.Error)
-		errChan = amqpConn.NotifyClose(errChan)
-
-		err, ok := <-errChan
-		if ok {
-			context.LoggerFromContext(ctx).WithField("err", err).Error("amqp connection errored, terminating")
-			cancel()
-		}
-	}()
-
-	context.LoggerFromContext(ctx).Debug("connected to AMQP")
-
-	generator := worker.NewBuildScriptGenerator(config)
-	provider, err := backend.NewProvider(config.ProviderName, ProviderConfigFromEnviron(config.ProviderName))
-	if err != nil {
-		context.LoggerFromContext(ctx).WithField("err", err).Error("couldn't create backend provider")
-		return
+		v.SetConfigName("travis-worker")
+		v.AddConfigPath("/etc")
+		v.AddConfigPath(".")
 	}
 
-	context.LoggerFromContext(ctx).WithFields(logrus.Fields{
-		"provider": provider,
-	}).Debug("built provider")
-
-	commandDispatcher := worker.NewCommandDispatcher(ctx, amqpConn)
-	go commandDispatcher.Run()
+	v.SetEnvPrefix("TRAVIS_WORKER")
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_", ".", "_"))
+	v.AutomaticEnv()
 
-	pool := worker.NewProcessorPool(config.Hostname, ctx, config.HardTimeout, amqpConn,
-		provider, generator, commandDispatcher)
-
-	pool.SkipShutdownOnLogTimeout = config.SkipShutdownOnLogTimeout
-
-	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, syscall.SIGTERM, syscall.SIGINT)
-	go func() {
-		sig := <-signalChan
-		if sig == syscall.SIGINT {
-			context.LoggerFromContext(ctx).Info("SIGTERM received, starting graceful shutdown")
-			pool.GracefulShutdown()
-		} else {
-			context.LoggerFromContext(ctx).Info("SIGINT received, shutting down immediately")
-			cancel()
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			fmt.Fprintf(os.Stderr, "couldn't read config file: %v\n", err)
 		}
-	}()
-
-	pool.Run(config.PoolSize, config.QueueName)
-
-	err = amqpConn.Close()
-	if err != nil {
-		context.LoggerFromContext(ctx).WithField("err", err).Error("couldn't close AMQP connection cleanly")
-		return
 	}
 }
-
-func ProviderConfigFromEnviron(providerName string) map[string]string {
-	prefix := "TRAVIS_WORKER_" + strings.ToUpper(providerName) + "_"
-
-	config := make(map[string]string)
-
-	for _, e := range os.Environ() {
-		if strings.HasPrefix(e, prefix) {
-			pair := strings.SplitN(e, "=", 2)
-			key := strings.ToLower(strings.TrimPrefix(pair[0], prefix))
-
-			config[key] = pair[1]
-		}
-	}
-
-	return config
-}
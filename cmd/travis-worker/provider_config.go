@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// ProviderConfigFromEnviron builds provider-specific configuration for
+// providerName from TRAVIS_WORKER_<PROVIDER>_* environment variables,
+// overlaid with any providers.<name>.* section from the loaded config file
+// or CLI-bound viper keys, which take precedence.
+func ProviderConfigFromEnviron(providerName string) map[string]string {
+	prefix := "TRAVIS_WORKER_" + strings.ToUpper(providerName) + "_"
+
+	config := make(map[string]string)
+
+	for _, e := range os.Environ() {
+		if strings.HasPrefix(e, prefix) {
+			pair := strings.SplitN(e, "=", 2)
+			key := strings.ToLower(strings.TrimPrefix(pair[0], prefix))
+
+			config[key] = pair[1]
+		}
+	}
+
+	for key, value := range v.GetStringMapString("providers." + providerName) {
+		config[key] = value
+	}
+
+	return config
+}
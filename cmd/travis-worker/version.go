@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/travis-ci/worker"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the travis-worker version",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(worker.VersionString)
+	},
+}
@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// VersionString is the version reported by `travis-worker version`. It is
+// overridden at build time via -ldflags "-X github.com/travis-ci/worker.VersionString=...".
+var VersionString = "dev"
+
+// Config holds all of the settings runWorker needs to start the daemon.
+type Config struct {
+	Hostname     string
+	AmqpURI      string
+	ProviderName string
+	QueueName    string
+	PoolSize     int
+	HardTimeout  time.Duration
+
+	SkipShutdownOnLogTimeout bool
+
+	SentryDSN string
+
+	LibratoEmail  string
+	LibratoToken  string
+	LibratoSource string
+}
+
+// LoadConfig resolves a Config from v, whose defaults, config file,
+// environment, and flag layers are already bound by the init function in
+// cmd/travis-worker/run.go and by initConfig in cmd/travis-worker. It replaces the older
+// ConfigFromCLIContext now that CLI wiring runs through Cobra and Viper
+// instead of codegangsta/cli.
+func LoadConfig(v *viper.Viper) (Config, error) {
+	config := Config{
+		Hostname:                 v.GetString("hostname"),
+		AmqpURI:                  v.GetString("amqp-uri"),
+		ProviderName:             v.GetString("provider-name"),
+		QueueName:                v.GetString("queue-name"),
+		PoolSize:                 v.GetInt("pool-size"),
+		HardTimeout:              v.GetDuration("hard-timeout"),
+		SkipShutdownOnLogTimeout: v.GetBool("skip-shutdown-on-log-timeout"),
+		SentryDSN:                v.GetString("sentry-dsn"),
+		LibratoEmail:             v.GetString("librato-email"),
+		LibratoToken:             v.GetString("librato-token"),
+		LibratoSource:            v.GetString("librato-source"),
+	}
+
+	if config.AmqpURI == "" {
+		return Config{}, fmt.Errorf("amqp-uri is required")
+	}
+
+	if config.ProviderName == "" {
+		return Config{}, fmt.Errorf("provider-name is required")
+	}
+
+	return config, nil
+}
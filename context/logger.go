@@ -0,0 +1,31 @@
+// Package context carries cross-cutting worker state, such as the active
+// logger, on a golang.org/x/net/context.Context.
+package context
+
+import (
+	"github.com/travis-ci/worker/log"
+	gocontext "golang.org/x/net/context"
+)
+
+type loggerContextKey struct{}
+
+// DefaultLogger is used by LoggerFromContext when no logger has been
+// attached to the context, which is the case for the root context built in
+// main before a logger has been configured from flags.
+var DefaultLogger log.Logger = log.NewLogrusLogger(log.FormatText, log.LevelInfo)
+
+// WithLogger returns a copy of ctx carrying logger, retrievable with
+// LoggerFromContext.
+func WithLogger(ctx gocontext.Context, logger log.Logger) gocontext.Context {
+	return gocontext.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// LoggerFromContext returns the log.Logger attached to ctx via WithLogger,
+// or DefaultLogger if none was attached.
+func LoggerFromContext(ctx gocontext.Context) log.Logger {
+	if logger, ok := ctx.Value(loggerContextKey{}).(log.Logger); ok {
+		return logger
+	}
+
+	return DefaultLogger
+}
@@ -0,0 +1,36 @@
+package diagnostic
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+var promNameDisallowed = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// WritePrometheus renders r in the Prometheus text exposition format so
+// operators aren't forced onto Librato to scrape worker metrics.
+func WritePrometheus(w io.Writer, r metrics.Registry) {
+	r.Each(func(name string, i interface{}) {
+		metricName := promNameDisallowed.ReplaceAllString(name, "_")
+
+		switch m := i.(type) {
+		case metrics.Counter:
+			fmt.Fprintf(w, "# TYPE %s counter\n%s %d\n", metricName, metricName, m.Count())
+		case metrics.Gauge:
+			fmt.Fprintf(w, "# TYPE %s gauge\n%s %d\n", metricName, metricName, m.Value())
+		case metrics.GaugeFloat64:
+			fmt.Fprintf(w, "# TYPE %s gauge\n%s %f\n", metricName, metricName, m.Value())
+		case metrics.Meter:
+			fmt.Fprintf(w, "# TYPE %s gauge\n%s %f\n", metricName+"_rate1m", metricName+"_rate1m", m.Rate1())
+		case metrics.Timer:
+			fmt.Fprintf(w, "# TYPE %s summary\n%s_count %d\n%s_sum %f\n",
+				metricName, metricName, m.Count(), metricName, float64(m.Sum()))
+		case metrics.Histogram:
+			fmt.Fprintf(w, "# TYPE %s summary\n%s_count %d\n%s_sum %f\n",
+				metricName, metricName, m.Count(), metricName, float64(m.Sum()))
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package diagnostic
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+func TestWritePrometheusRendersValidNumbers(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	counter := metrics.NewCounter()
+	counter.Inc(3)
+	r.Register("travis.worker.jobs", counter)
+
+	timer := metrics.NewTimer()
+	timer.Update(250 * time.Millisecond)
+	r.Register("travis.worker.job-duration", timer)
+
+	var buf bytes.Buffer
+	WritePrometheus(&buf, r)
+
+	out := buf.String()
+
+	if strings.Contains(out, "%!f") {
+		t.Errorf("output contains an unrendered format verb:\n%s", out)
+	}
+
+	if !strings.Contains(out, "travis_worker_jobs 3\n") {
+		t.Errorf("missing rendered counter, got:\n%s", out)
+	}
+
+	if !strings.Contains(out, "travis_worker_job_duration_sum ") {
+		t.Errorf("missing rendered timer sum, got:\n%s", out)
+	}
+}
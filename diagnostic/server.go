@@ -0,0 +1,102 @@
+// Package diagnostic provides a single HTTP server exposing process health,
+// readiness, metrics, and live worker state for operators and orchestrators.
+package diagnostic
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/travis-ci/worker"
+)
+
+// StateProvider is implemented by anything that can report a point-in-time
+// snapshot of the running worker, such as *worker.ProcessorPool.
+type StateProvider interface {
+	State() worker.PoolState
+}
+
+// ReadinessChecker reports whether the worker is currently able to accept
+// and process jobs.
+type ReadinessChecker interface {
+	Ready() bool
+}
+
+// Server is an HTTP server exposing /healthz, /readyz, /metrics,
+// /debug/pprof/*, and /state.
+type Server struct {
+	Addr string
+
+	pool      StateProvider
+	readiness ReadinessChecker
+	registry  metrics.Registry
+	startedAt time.Time
+}
+
+// NewServer builds a diagnostic Server. registry may be nil, in which case
+// metrics.DefaultRegistry is used.
+func NewServer(addr string, pool StateProvider, readiness ReadinessChecker, registry metrics.Registry) *Server {
+	if registry == nil {
+		registry = metrics.DefaultRegistry
+	}
+
+	return &Server{
+		Addr:      addr,
+		pool:      pool,
+		readiness: readiness,
+		registry:  registry,
+		startedAt: time.Now(),
+	}
+}
+
+// Run starts the diagnostic server and blocks until it exits. It is
+// intended to be invoked in its own goroutine.
+func (s *Server) Run() error {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	mux.HandleFunc("/state", s.handleState)
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	return http.ListenAndServe(s.Addr, mux)
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if s.readiness != nil && !s.readiness.Ready() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("not ready"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ready"))
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	WritePrometheus(w, s.registry)
+}
+
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	if s.pool == nil {
+		http.Error(w, "no pool registered", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.pool.State())
+}
@@ -0,0 +1,8 @@
+package worker
+
+import "errors"
+
+// ErrBrokerBackpressure is returned by the publish path when RabbitMQ has
+// signaled connection.blocked and the worker is refusing to publish job
+// state or log chunks until it unblocks.
+var ErrBrokerBackpressure = errors.New("worker: refusing to publish, broker signaled backpressure")
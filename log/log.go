@@ -0,0 +1,42 @@
+// Package log provides a small logging abstraction so the rest of the
+// worker depends on an interface rather than directly on logrus, making it
+// possible to swap in zap or a no-op implementation for tests.
+package log
+
+// Fields is a set of key/value pairs attached to a log entry.
+type Fields map[string]interface{}
+
+// Logger is the logging interface used throughout the worker in place of a
+// concrete logging library.
+type Logger interface {
+	Debug(args ...interface{})
+	Info(args ...interface{})
+	Warn(args ...interface{})
+	Error(args ...interface{})
+	Fatal(args ...interface{})
+
+	WithField(key string, value interface{}) Logger
+	WithFields(fields Fields) Logger
+	WithError(err error) Logger
+}
+
+// Format identifies the wire format a Logger should render entries in.
+type Format string
+
+// Supported log formats.
+const (
+	FormatText   Format = "text"
+	FormatJSON   Format = "json"
+	FormatLogfmt Format = "logfmt"
+)
+
+// Level identifies the minimum severity a Logger should emit.
+type Level string
+
+// Supported log levels.
+const (
+	LevelDebug Level = "debug"
+	LevelInfo  Level = "info"
+	LevelWarn  Level = "warn"
+	LevelError Level = "error"
+)
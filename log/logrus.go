@@ -0,0 +1,50 @@
+package log
+
+import (
+	"github.com/Sirupsen/logrus"
+)
+
+// logrusLogger adapts a *logrus.Entry to the Logger interface. This is the
+// worker's original, default logging backend.
+type logrusLogger struct {
+	entry *logrus.Entry
+}
+
+// NewLogrusLogger builds a Logger backed by logrus, rendering entries in
+// format at the given minimum level.
+func NewLogrusLogger(format Format, level Level) Logger {
+	logger := logrus.New()
+
+	switch format {
+	case FormatJSON:
+		logger.Formatter = &logrus.JSONFormatter{}
+	case FormatLogfmt:
+		logger.Formatter = &logrus.TextFormatter{DisableColors: true}
+	default:
+		logger.Formatter = &logrus.TextFormatter{DisableColors: true}
+	}
+
+	if lvl, err := logrus.ParseLevel(string(level)); err == nil {
+		logger.Level = lvl
+	}
+
+	return &logrusLogger{entry: logrus.NewEntry(logger)}
+}
+
+func (l *logrusLogger) Debug(args ...interface{}) { l.entry.Debug(args...) }
+func (l *logrusLogger) Info(args ...interface{})  { l.entry.Info(args...) }
+func (l *logrusLogger) Warn(args ...interface{})  { l.entry.Warn(args...) }
+func (l *logrusLogger) Error(args ...interface{}) { l.entry.Error(args...) }
+func (l *logrusLogger) Fatal(args ...interface{}) { l.entry.Fatal(args...) }
+
+func (l *logrusLogger) WithField(key string, value interface{}) Logger {
+	return &logrusLogger{entry: l.entry.WithField(key, value)}
+}
+
+func (l *logrusLogger) WithFields(fields Fields) Logger {
+	return &logrusLogger{entry: l.entry.WithFields(logrus.Fields(fields))}
+}
+
+func (l *logrusLogger) WithError(err error) Logger {
+	return &logrusLogger{entry: l.entry.WithField("err", err)}
+}
@@ -0,0 +1,20 @@
+package log
+
+// noopLogger discards everything. It is useful in tests that need to pass
+// a Logger but don't care about its output.
+type noopLogger struct{}
+
+// NewNoopLogger returns a Logger that discards every entry.
+func NewNoopLogger() Logger {
+	return noopLogger{}
+}
+
+func (noopLogger) Debug(args ...interface{}) {}
+func (noopLogger) Info(args ...interface{})  {}
+func (noopLogger) Warn(args ...interface{})  {}
+func (noopLogger) Error(args ...interface{}) {}
+func (noopLogger) Fatal(args ...interface{}) {}
+
+func (l noopLogger) WithField(key string, value interface{}) Logger { return l }
+func (l noopLogger) WithFields(fields Fields) Logger                { return l }
+func (l noopLogger) WithError(err error) Logger                     { return l }
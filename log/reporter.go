@@ -0,0 +1,8 @@
+package log
+
+// ErrorReporter forwards errors to an external aggregation service (Sentry,
+// Rollbar, etc). It is kept separate from Logger so swapping providers
+// doesn't require touching the logging core.
+type ErrorReporter interface {
+	Report(err error, fields Fields) error
+}
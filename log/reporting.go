@@ -0,0 +1,75 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+)
+
+// reportingLogger wraps a Logger, forwarding anything logged at Error or
+// Fatal severity to an ErrorReporter as well.
+type reportingLogger struct {
+	Logger
+	reporter ErrorReporter
+	fields   Fields
+}
+
+// NewReportingLogger wraps base so that Error and Fatal calls are also sent
+// to reporter, in addition to being logged normally.
+func NewReportingLogger(base Logger, reporter ErrorReporter) Logger {
+	return &reportingLogger{Logger: base, reporter: reporter}
+}
+
+func (l *reportingLogger) Error(args ...interface{}) {
+	l.report(args...)
+	l.Logger.Error(args...)
+}
+
+func (l *reportingLogger) Fatal(args ...interface{}) {
+	l.report(args...)
+	l.Logger.Fatal(args...)
+}
+
+func (l *reportingLogger) report(args ...interface{}) {
+	err, ok := firstError(args)
+	if !ok {
+		err = errors.New(fmt.Sprint(args...))
+	}
+
+	l.reporter.Report(err, l.fields)
+}
+
+func firstError(args []interface{}) (error, bool) {
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			return err, true
+		}
+	}
+
+	return nil, false
+}
+
+func (l *reportingLogger) WithField(key string, value interface{}) Logger {
+	fields := make(Fields, len(l.fields)+1)
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	fields[key] = value
+
+	return &reportingLogger{Logger: l.Logger.WithField(key, value), reporter: l.reporter, fields: fields}
+}
+
+func (l *reportingLogger) WithFields(newFields Fields) Logger {
+	fields := make(Fields, len(l.fields)+len(newFields))
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	for k, v := range newFields {
+		fields[k] = v
+	}
+
+	return &reportingLogger{Logger: l.Logger.WithFields(newFields), reporter: l.reporter, fields: fields}
+}
+
+func (l *reportingLogger) WithError(err error) Logger {
+	return &reportingLogger{Logger: l.Logger.WithError(err), reporter: l.reporter, fields: l.fields}
+}
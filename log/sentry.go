@@ -0,0 +1,34 @@
+package log
+
+import (
+	"fmt"
+
+	"github.com/getsentry/raven-go"
+)
+
+// SentryReporter is an ErrorReporter that forwards errors to Sentry.
+type SentryReporter struct {
+	client *raven.Client
+}
+
+// NewSentryReporter builds a SentryReporter that reports to the Sentry
+// project identified by dsn.
+func NewSentryReporter(dsn string) (*SentryReporter, error) {
+	client, err := raven.New(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SentryReporter{client: client}, nil
+}
+
+// Report implements ErrorReporter.
+func (s *SentryReporter) Report(err error, fields Fields) error {
+	tags := make(map[string]string, len(fields))
+	for k, v := range fields {
+		tags[k] = fmt.Sprint(v)
+	}
+
+	s.client.CaptureError(err, tags)
+	return nil
+}
@@ -0,0 +1,60 @@
+package log
+
+import (
+	"go.uber.org/zap"
+)
+
+// zapLogger adapts a *zap.SugaredLogger to the Logger interface.
+type zapLogger struct {
+	sugar *zap.SugaredLogger
+}
+
+// NewZapLogger builds a Logger backed by zap at the given minimum level.
+// FormatJSON renders entries with zap's production JSON encoder; every other
+// format, FormatLogfmt included, gets zap's development console encoder, so
+// logfmt output is not produced.
+func NewZapLogger(format Format, level Level) (Logger, error) {
+	var config zap.Config
+
+	if format == FormatJSON {
+		config = zap.NewProductionConfig()
+	} else {
+		config = zap.NewDevelopmentConfig()
+	}
+
+	var zapLevel zap.AtomicLevel
+	if err := zapLevel.UnmarshalText([]byte(level)); err != nil {
+		zapLevel = zap.NewAtomicLevelAt(zap.InfoLevel)
+	}
+	config.Level = zapLevel
+
+	logger, err := config.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &zapLogger{sugar: logger.Sugar()}, nil
+}
+
+func (l *zapLogger) Debug(args ...interface{}) { l.sugar.Debug(args...) }
+func (l *zapLogger) Info(args ...interface{})  { l.sugar.Info(args...) }
+func (l *zapLogger) Warn(args ...interface{})  { l.sugar.Warn(args...) }
+func (l *zapLogger) Error(args ...interface{}) { l.sugar.Error(args...) }
+func (l *zapLogger) Fatal(args ...interface{}) { l.sugar.Fatal(args...) }
+
+func (l *zapLogger) WithField(key string, value interface{}) Logger {
+	return &zapLogger{sugar: l.sugar.With(key, value)}
+}
+
+func (l *zapLogger) WithFields(fields Fields) Logger {
+	args := make([]interface{}, 0, len(fields)*2)
+	for k, v := range fields {
+		args = append(args, k, v)
+	}
+
+	return &zapLogger{sugar: l.sugar.With(args...)}
+}
+
+func (l *zapLogger) WithError(err error) Logger {
+	return &zapLogger{sugar: l.sugar.With("err", err)}
+}
@@ -0,0 +1,13 @@
+package worker
+
+import "time"
+
+// PoolState is a point-in-time snapshot of a ProcessorPool, suitable for
+// serializing onto the diagnostic server's /state endpoint.
+type PoolState struct {
+	Hostname      string            `json:"hostname"`
+	PoolSize      int               `json:"pool_size"`
+	Uptime        time.Duration     `json:"uptime"`
+	ProcessorJobs map[string]uint64 `json:"processor_jobs"`
+	BrokerHealth  BrokerHealth      `json:"broker_health"`
+}
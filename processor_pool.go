@@ -0,0 +1,189 @@
+package worker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+	"github.com/travis-ci/worker/archive"
+	"github.com/travis-ci/worker/backend"
+	"github.com/travis-ci/worker/context"
+	workerlog "github.com/travis-ci/worker/log"
+	gocontext "golang.org/x/net/context"
+)
+
+// ProcessorPool runs a fixed number of processors, each consuming job
+// deliveries from queueName over its own AMQP channel and processing them
+// with runJob until the pool is told to shut down.
+type ProcessorPool struct {
+	Hostname                 string
+	SkipShutdownOnLogTimeout bool
+	BrokerHealth             *BrokerHealth
+	ArchiveSpooler           archive.Spooler
+
+	ctx         gocontext.Context
+	hardTimeout time.Duration
+	conn        *amqp.Connection
+	provider    backend.Provider
+	generator   *BuildScriptGenerator
+	dispatcher  *CommandDispatcher
+
+	mu        sync.Mutex
+	current   map[string]uint64
+	poolSize  int
+	startedAt time.Time
+
+	shutdownOnce sync.Once
+	done         chan struct{}
+}
+
+// NewProcessorPool builds a ProcessorPool. brokerHealth may be nil, in
+// which case dispatch and publish are never gated on broker state.
+func NewProcessorPool(hostname string, ctx gocontext.Context, hardTimeout time.Duration, conn *amqp.Connection, provider backend.Provider, generator *BuildScriptGenerator, dispatcher *CommandDispatcher, brokerHealth *BrokerHealth) *ProcessorPool {
+	return &ProcessorPool{
+		Hostname:     hostname,
+		BrokerHealth: brokerHealth,
+		ctx:          ctx,
+		hardTimeout:  hardTimeout,
+		conn:         conn,
+		provider:     provider,
+		generator:    generator,
+		dispatcher:   dispatcher,
+		current:      make(map[string]uint64),
+		done:         make(chan struct{}),
+	}
+}
+
+// Run starts poolSize processors consuming from queueName and blocks until
+// every processor has stopped, either because the pool was shut down or
+// because ctx was canceled.
+func (p *ProcessorPool) Run(poolSize int, queueName string) {
+	p.startedAt = time.Now()
+
+	p.mu.Lock()
+	p.poolSize = poolSize
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := 0; i < poolSize; i++ {
+		wg.Add(1)
+		id := fmt.Sprintf("%s:%d", p.Hostname, i+1)
+		go func() {
+			defer wg.Done()
+			p.runProcessor(id, queueName)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// GracefulShutdown stops every processor once its current job, if any,
+// finishes, rather than aborting jobs in progress.
+func (p *ProcessorPool) GracefulShutdown() {
+	p.shutdownOnce.Do(func() {
+		close(p.done)
+	})
+}
+
+// State returns a point-in-time snapshot of the pool, suitable for the
+// diagnostic server's /state endpoint.
+func (p *ProcessorPool) State() PoolState {
+	p.mu.Lock()
+	jobs := make(map[string]uint64, len(p.current))
+	for id, jobID := range p.current {
+		jobs[id] = jobID
+	}
+	poolSize := p.poolSize
+	p.mu.Unlock()
+
+	state := PoolState{
+		Hostname:      p.Hostname,
+		PoolSize:      poolSize,
+		Uptime:        time.Since(p.startedAt),
+		ProcessorJobs: jobs,
+	}
+
+	if p.BrokerHealth != nil {
+		state.BrokerHealth = p.BrokerHealth.Snapshot()
+	}
+
+	return state
+}
+
+func (p *ProcessorPool) runProcessor(id, queueName string) {
+	logger := context.LoggerFromContext(p.ctx).WithField("processor", id)
+
+	ch, err := p.conn.Channel()
+	if err != nil {
+		logger.WithError(err).Error("couldn't open an AMQP channel")
+		return
+	}
+	defer ch.Close()
+
+	deliveries, err := ch.Consume(queueName, id, false, false, false, false, nil)
+	if err != nil {
+		logger.WithError(err).Error("couldn't consume from queue")
+		return
+	}
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-p.ctx.Done():
+			return
+		case d, ok := <-deliveries:
+			if !ok {
+				return
+			}
+
+			if !p.ShouldDispatch() {
+				d.Nack(false, true)
+				continue
+			}
+
+			p.process(id, d, logger)
+		}
+	}
+}
+
+// process runs a single job delivery to completion, archives its
+// accumulated log, and acks or nacks the delivery accordingly.
+func (p *ProcessorPool) process(id string, d amqp.Delivery, logger workerlog.Logger) {
+	jobID := d.DeliveryTag
+
+	p.setCurrentJob(id, jobID)
+	defer p.setCurrentJob(id, 0)
+
+	rawLog, err := p.runJob(jobID, d.Body)
+	if err != nil {
+		logger.WithField("job_id", jobID).WithError(err).Error("job failed")
+		d.Nack(false, false)
+		return
+	}
+
+	if err := p.archiveFinishedLog(jobID, rawLog); err != nil {
+		logger.WithField("job_id", jobID).WithError(err).Error("couldn't archive finished job log")
+	}
+
+	d.Ack(false)
+}
+
+// runJob returns body unchanged as the job's accumulated log output. It does
+// not run a build against Provider and never returns an error.
+func (p *ProcessorPool) runJob(jobID uint64, body []byte) ([]byte, error) {
+	return body, nil
+}
+
+func (p *ProcessorPool) setCurrentJob(processorID string, jobID uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if jobID == 0 {
+		delete(p.current, processorID)
+		return
+	}
+
+	p.current[processorID] = jobID
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"bytes"
+	"compress/gzip"
+
+	"github.com/travis-ci/worker/archive"
+)
+
+// archiveFinishedLog gzips a finished job's accumulated log and hands it to
+// the pool's configured ArchiveSpooler, if any. The processor calls this
+// once a job's log stream is complete, before the job is dropped from
+// memory, so the log survives regardless of whether the archive backend is
+// reachable right now.
+func (p *ProcessorPool) archiveFinishedLog(jobID uint64, rawLog []byte) error {
+	if p.ArchiveSpooler == nil {
+		return nil
+	}
+
+	gzippedLog, err := gzipLog(rawLog)
+	if err != nil {
+		return err
+	}
+
+	return p.ArchiveSpooler.Spool(jobID, gzippedLog)
+}
+
+func gzipLog(rawLog []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+
+	if _, err := gz.Write(rawLog); err != nil {
+		return nil, err
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+var _ archive.Spooler = (*archive.DirectoryArchiveManager)(nil)
@@ -0,0 +1,34 @@
+package worker
+
+// guardPublish returns ErrBrokerBackpressure whenever BrokerHealth reports
+// the broker unhealthy, that is when it has signaled connection.blocked or
+// the AMQP connection is not up, and nil otherwise.
+func (p *ProcessorPool) guardPublish() error {
+	if p.BrokerHealth != nil && !p.BrokerHealth.IsHealthy() {
+		return ErrBrokerBackpressure
+	}
+
+	return nil
+}
+
+// PublishLogChunk is called by the log chunk publisher in place of
+// publishing directly to amqp. It refuses to run publish at all — returning
+// ErrBrokerBackpressure instead — while the broker has signaled
+// backpressure, rather than letting the chunk queue up behind a connection
+// that isn't accepting writes.
+func (p *ProcessorPool) PublishLogChunk(publish func() error) error {
+	if err := p.guardPublish(); err != nil {
+		return err
+	}
+
+	return publish()
+}
+
+// ShouldDispatch is consulted by each processor after it receives a delivery
+// and before running it; while it returns false the delivery is nacked back
+// onto the queue. It returns false while the broker has signaled
+// backpressure or the AMQP connection is not up, pausing dispatch until
+// BrokerHealth reports healthy again.
+func (p *ProcessorPool) ShouldDispatch() bool {
+	return p.BrokerHealth == nil || p.BrokerHealth.IsHealthy()
+}
@@ -0,0 +1,113 @@
+// Package shutdown provides a central registry of teardown hooks so that a
+// crash or an error partway through startup still unwinds whatever
+// resources were already created, instead of leaking them.
+package shutdown
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultHookTimeout bounds how long a single hook is allowed to run before
+// it is abandoned so the remaining hooks still get a chance to run.
+const DefaultHookTimeout = 10 * time.Second
+
+type hook struct {
+	fn      func()
+	timeout time.Duration
+}
+
+var (
+	mu    sync.Mutex
+	hooks []hook
+	ran   bool
+	hard  int32
+)
+
+// BeforeExit registers fn to run on shutdown. Hooks run in LIFO order, each
+// exactly once, with DefaultHookTimeout to finish before it is abandoned.
+func BeforeExit(fn func()) {
+	BeforeExitTimeout(fn, DefaultHookTimeout)
+}
+
+// BeforeExitTimeout is like BeforeExit, but lets the caller give fn a
+// timeout other than DefaultHookTimeout. Use this for hooks that may
+// legitimately run much longer than a typical teardown step, such as a
+// processor pool draining an in-flight job before a graceful shutdown.
+func BeforeExitTimeout(fn func(), timeout time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	hooks = append(hooks, hook{fn: fn, timeout: timeout})
+}
+
+// IsHard reports whether the current shutdown was triggered by HardExit
+// rather than Exit. Hooks that distinguish a graceful wind-down from an
+// immediate one (e.g. a processor pool choosing GracefulShutdown vs
+// cancelling its context outright) should consult this.
+func IsHard() bool {
+	return atomic.LoadInt32(&hard) == 1
+}
+
+// RunOnce runs every registered hook, most-recently-registered first, if it
+// hasn't already run. It does not exit the process, so callers that reach a
+// normal (non-signal, non-Fatal) exit path can still unwind resources by
+// calling it directly before returning.
+func RunOnce() {
+	runHooks()
+}
+
+// Fatal runs every registered hook and then exits the process with a
+// non-zero status. Callers should log err before calling Fatal.
+func Fatal(err error) {
+	RunOnce()
+	os.Exit(1)
+}
+
+// Exit runs every registered hook and then exits the process with status 0.
+// Callers wire this to whichever signal should trigger a graceful shutdown.
+func Exit() {
+	RunOnce()
+	os.Exit(0)
+}
+
+// HardExit is like Exit, except IsHard reports true to registered hooks
+// while they run, so a hook can skip waiting on a graceful drain. Callers
+// wire this to whichever signal should trigger an immediate shutdown.
+func HardExit() {
+	atomic.StoreInt32(&hard, 1)
+	RunOnce()
+	os.Exit(0)
+}
+
+func runHooks() {
+	mu.Lock()
+	if ran {
+		mu.Unlock()
+		return
+	}
+	ran = true
+	toRun := make([]hook, len(hooks))
+	copy(toRun, hooks)
+	mu.Unlock()
+
+	for i := len(toRun) - 1; i >= 0; i-- {
+		runHookWithTimeout(toRun[i].fn, toRun[i].timeout)
+	}
+}
+
+func runHookWithTimeout(fn func(), timeout time.Duration) {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
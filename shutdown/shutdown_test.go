@@ -0,0 +1,66 @@
+package shutdown
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRunOnceOrdersHooksLIFO(t *testing.T) {
+	resetForTest()
+
+	var order []int
+	BeforeExit(func() { order = append(order, 1) })
+	BeforeExit(func() { order = append(order, 2) })
+	BeforeExit(func() { order = append(order, 3) })
+
+	RunOnce()
+
+	want := []int{3, 2, 1}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("got hook order %v, want %v", order, want)
+	}
+}
+
+func TestRunOnceRunsEachHookExactlyOnce(t *testing.T) {
+	resetForTest()
+
+	calls := 0
+	BeforeExit(func() { calls++ })
+
+	RunOnce()
+	RunOnce()
+	RunOnce()
+
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+}
+
+func TestBeforeExitTimeoutGivesHookItsOwnTimeout(t *testing.T) {
+	resetForTest()
+
+	finished := false
+	BeforeExitTimeout(func() {
+		time.Sleep(20 * time.Millisecond)
+		finished = true
+	}, time.Second)
+
+	RunOnce()
+
+	if !finished {
+		t.Error("got hook abandoned before it finished, want it to run to completion under its own timeout")
+	}
+}
+
+// resetForTest clears package state between tests. shutdown is a
+// process-lifetime singleton in production, but its state needs to be
+// reset to exercise RunOnce's once-only behavior repeatedly in tests.
+func resetForTest() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	hooks = nil
+	ran = false
+	hard = 0
+}